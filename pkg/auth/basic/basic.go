@@ -0,0 +1,60 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package basic implements a plain HTTP Basic auth strategy against Service
+// Manager, useful for local development or bootstrap credentials that do not
+// go through the configured identity provider.
+package basic
+
+import (
+	"encoding/base64"
+	"errors"
+
+	"github.com/Peripli/service-manager-cli/pkg/auth"
+)
+
+// StrategyName is the name this strategy registers itself under.
+const StrategyName = "basic"
+
+func init() {
+	auth.RegisterStrategy(StrategyName, func(options *auth.Options) (auth.Authenticator, error) {
+		return New(options), nil
+	})
+}
+
+// Strategy authenticates with the user/password from auth.Options using HTTP Basic auth.
+type Strategy struct {
+	options *auth.Options
+}
+
+// New returns a basic auth Strategy for the given options.
+func New(options *auth.Options) *Strategy {
+	return &Strategy{options: options}
+}
+
+// Authenticate returns the configured credentials base64-encoded as a Basic token.
+// The token never expires, as SM validates the credentials on every request.
+func (s *Strategy) Authenticate() (*auth.Token, error) {
+	if s.options.User == "" {
+		return nil, errors.New("basic auth strategy requires a user")
+	}
+
+	credentials := s.options.User + ":" + s.options.Password
+	return &auth.Token{
+		AccessToken: base64.StdEncoding.EncodeToString([]byte(credentials)),
+		TokenType:   "Basic",
+	}, nil
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package basic
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/Peripli/service-manager-cli/pkg/auth"
+)
+
+func TestAuthenticate(t *testing.T) {
+	strategy := New(&auth.Options{User: "jdoe", Password: "s3cret"})
+
+	token, err := strategy.Authenticate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.TokenType != "Basic" {
+		t.Fatalf("expected token type Basic, got %q", token.TokenType)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(token.AccessToken)
+	if err != nil {
+		t.Fatalf("expected access token to be base64 encoded: %v", err)
+	}
+	if string(decoded) != "jdoe:s3cret" {
+		t.Fatalf("expected decoded credentials %q, got %q", "jdoe:s3cret", decoded)
+	}
+}
+
+func TestAuthenticateRequiresUser(t *testing.T) {
+	strategy := New(&auth.Options{Password: "s3cret"})
+
+	if _, err := strategy.Authenticate(); err == nil {
+		t.Fatal("expected an error when no user is configured")
+	}
+}
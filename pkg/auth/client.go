@@ -0,0 +1,75 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package auth
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// genericClient is a Client that attaches the token produced by an Authenticator
+// to every request, re-invoking it once the cached token is at or past its
+// expiry. It is the default wiring for strategies (basic, static-token, exec)
+// that do not have a dedicated refresh flow of their own, unlike oidc.Client.
+type genericClient struct {
+	authenticator Authenticator
+	httpClient    *http.Client
+
+	mutex sync.Mutex
+	token *Token
+}
+
+// NewGenericClient returns a Client that authenticates every request with the
+// token authenticator produces, re-authenticating once that token expires.
+func NewGenericClient(authenticator Authenticator, httpClient *http.Client) Client {
+	return &genericClient{authenticator: authenticator, httpClient: httpClient}
+}
+
+// Token returns the cached token, re-authenticating if it is missing or expired.
+func (c *genericClient) Token() (*Token, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.token != nil && (c.token.ExpiresIn.IsZero() || time.Now().Before(c.token.ExpiresIn)) {
+		return c.token, nil
+	}
+
+	token, err := c.authenticator.Authenticate()
+	if err != nil {
+		return nil, err
+	}
+	c.token = token
+
+	return token, nil
+}
+
+// Do attaches the current token as an Authorization header and performs the request.
+func (c *genericClient) Do(req *http.Request) (*http.Response, error) {
+	token, err := c.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	tokenType := token.TokenType
+	if tokenType == "" {
+		tokenType = "Bearer"
+	}
+	req.Header.Set("Authorization", tokenType+" "+token.AccessToken)
+
+	return c.httpClient.Do(req)
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package exec implements an auth strategy that delegates authentication to an
+// external binary, so corporate SSO helpers can be integrated without teaching
+// this CLI about their protocol.
+package exec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/Peripli/service-manager-cli/pkg/auth"
+)
+
+// StrategyName is the name this strategy registers itself under.
+const StrategyName = "exec"
+
+func init() {
+	auth.RegisterStrategy(StrategyName, func(options *auth.Options) (auth.Authenticator, error) {
+		return New(options), nil
+	})
+}
+
+// credential is the JSON contract the external command must print to stdout.
+type credential struct {
+	Token  string    `json:"token"`
+	Expiry time.Time `json:"expiry"`
+}
+
+// Strategy authenticates by running an external command and parsing a JSON
+// {token, expiry} object from its stdout.
+type Strategy struct {
+	options *auth.Options
+}
+
+// New returns an exec Strategy for the given options.
+func New(options *auth.Options) *Strategy {
+	return &Strategy{options: options}
+}
+
+// Authenticate runs the configured command and parses its output into a Token.
+func (s *Strategy) Authenticate() (*auth.Token, error) {
+	if s.options.ExecCommand == "" {
+		return nil, errors.New("exec auth strategy requires a command")
+	}
+
+	cmd := exec.Command(s.options.ExecCommand, s.options.ExecArgs...)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("exec auth command failed: %v", err)
+	}
+
+	var cred credential
+	if err := json.Unmarshal(stdout.Bytes(), &cred); err != nil {
+		return nil, fmt.Errorf("could not parse exec auth command output: %v", err)
+	}
+	if cred.Token == "" {
+		return nil, errors.New("exec auth command did not return a token")
+	}
+
+	return &auth.Token{
+		AccessToken: cred.Token,
+		TokenType:   "Bearer",
+		ExpiresIn:   cred.Expiry,
+	}, nil
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package exec
+
+import (
+	"testing"
+
+	"github.com/Peripli/service-manager-cli/pkg/auth"
+)
+
+func TestAuthenticate(t *testing.T) {
+	strategy := New(&auth.Options{
+		ExecCommand: "/bin/sh",
+		ExecArgs:    []string{"-c", `echo '{"token":"exec-token","expiry":"2030-01-01T00:00:00Z"}'`},
+	})
+
+	token, err := strategy.Authenticate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.AccessToken != "exec-token" {
+		t.Fatalf("expected access token %q, got %q", "exec-token", token.AccessToken)
+	}
+	if token.TokenType != "Bearer" {
+		t.Fatalf("expected token type Bearer, got %q", token.TokenType)
+	}
+}
+
+func TestAuthenticateRequiresCommand(t *testing.T) {
+	strategy := New(&auth.Options{})
+
+	if _, err := strategy.Authenticate(); err == nil {
+		t.Fatal("expected an error when no command is configured")
+	}
+}
+
+func TestAuthenticateRequiresTokenInOutput(t *testing.T) {
+	strategy := New(&auth.Options{
+		ExecCommand: "/bin/sh",
+		ExecArgs:    []string{"-c", `echo '{"expiry":"2030-01-01T00:00:00Z"}'`},
+	})
+
+	if _, err := strategy.Authenticate(); err == nil {
+		t.Fatal("expected an error when the command output has no token")
+	}
+}
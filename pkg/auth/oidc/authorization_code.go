@@ -0,0 +1,209 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/Peripli/service-manager-cli/internal/util"
+	"github.com/Peripli/service-manager-cli/pkg/auth"
+)
+
+// StrategyAuthorizationCode is the name under which the PKCE authorization_code
+// flow is known to configuration and the authentication strategy registry.
+const StrategyAuthorizationCode = "authorization_code"
+
+const (
+	callbackPath            = "/callback"
+	callbackTimeout         = 5 * time.Minute
+	codeVerifierLength      = 64
+	codeChallengeMethodS256 = "S256"
+)
+
+func init() {
+	auth.RegisterStrategy(StrategyAuthorizationCode, func(options *auth.Options) (auth.Authenticator, error) {
+		return NewAuthorizationCodeStrategy(options)
+	})
+}
+
+// AuthorizationCodeStrategy implements auth.Authenticator by running an OAuth2
+// authorization_code flow with PKCE (RFC 7636) through the system browser. It is
+// meant for interactive logins where no client secret can be kept confidential.
+type AuthorizationCodeStrategy struct {
+	options *auth.Options
+}
+
+// NewAuthorizationCodeStrategy returns an AuthorizationCodeStrategy for the given options,
+// resolving the authorization and token endpoints from the issuer's openid-configuration
+// document if they have not already been populated.
+func NewAuthorizationCodeStrategy(options *auth.Options) (auth.Authenticator, error) {
+	if options.AuthorizationEndpoint == "" || options.TokenEndpoint == "" {
+		httpClient := util.BuildHTTPClient(options.SSLDisabled)
+		configuration, err := fetchOpenidConfiguration(options.IssuerURL, httpClient.Do)
+		if err != nil {
+			return nil, err
+		}
+		options.AuthorizationEndpoint = configuration.AuthorizationEndpoint
+		options.TokenEndpoint = configuration.TokenEndpoint
+	}
+
+	return &AuthorizationCodeStrategy{options: options}, nil
+}
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// Authenticate opens the authorization endpoint in the user's browser, waits for the
+// loopback redirect carrying the authorization code, and exchanges it for a token.
+func (s *AuthorizationCodeStrategy) Authenticate() (*auth.Token, error) {
+	verifier, err := generateCodeVerifier()
+	if err != nil {
+		return nil, err
+	}
+	state, err := generateCodeVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("could not start loopback listener: %v", err)
+	}
+	redirectURI := fmt.Sprintf("http://127.0.0.1:%d%s", listener.Addr().(*net.TCPAddr).Port, callbackPath)
+
+	resultCh := make(chan callbackResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(callbackPath, callbackHandler(state, resultCh))
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	authorizationURL := s.buildAuthorizationURL(redirectURI, state, codeChallenge(verifier))
+	if err := openBrowser(authorizationURL); err != nil {
+		fmt.Printf("could not open browser, visit the following URL to login: %s\n", authorizationURL)
+	}
+
+	select {
+	case result := <-resultCh:
+		if result.err != nil {
+			return nil, result.err
+		}
+		return s.exchangeCode(result.code, verifier, redirectURI)
+	case <-time.After(callbackTimeout):
+		return nil, errors.New("timed out waiting for the authorization callback")
+	}
+}
+
+func (s *AuthorizationCodeStrategy) buildAuthorizationURL(redirectURI, state, challenge string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", s.options.ClientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("scope", "openid offline_access")
+	query.Set("state", state)
+	query.Set("code_challenge", challenge)
+	query.Set("code_challenge_method", codeChallengeMethodS256)
+
+	return s.options.AuthorizationEndpoint + "?" + query.Encode()
+}
+
+func (s *AuthorizationCodeStrategy) exchangeCode(code, verifier, redirectURI string) (*auth.Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("code_verifier", verifier)
+	form.Set("client_id", s.options.ClientID)
+	form.Set("redirect_uri", redirectURI)
+	if s.options.ClientSecret != "" {
+		form.Set("client_secret", s.options.ClientSecret)
+	}
+
+	return postTokenRequest(s.options, form)
+}
+
+func callbackHandler(expectedState string, resultCh chan<- callbackResult) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		if errMsg := query.Get("error"); errMsg != "" {
+			resultCh <- callbackResult{err: fmt.Errorf("authorization failed: %s", errMsg)}
+			respondAndClose(w, "Login failed, you can close this window.")
+			return
+		}
+
+		if query.Get("state") != expectedState {
+			resultCh <- callbackResult{err: errors.New("state mismatch in authorization callback")}
+			respondAndClose(w, "Login failed, you can close this window.")
+			return
+		}
+
+		code := query.Get("code")
+		if code == "" {
+			resultCh <- callbackResult{err: errors.New("authorization callback did not contain a code")}
+			respondAndClose(w, "Login failed, you can close this window.")
+			return
+		}
+
+		resultCh <- callbackResult{code: code}
+		respondAndClose(w, "Login successful, you can close this window.")
+	}
+}
+
+func respondAndClose(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(message))
+}
+
+// generateCodeVerifier returns a cryptographically random, base64url-encoded string
+// suitable for use as a PKCE code_verifier or as callback state.
+func generateCodeVerifier() (string, error) {
+	buf := make([]byte, codeVerifierLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// codeChallenge derives the S256 PKCE code_challenge from a code_verifier.
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// openBrowser opens url in the user's default browser.
+func openBrowser(url string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+	default:
+		return exec.Command("xdg-open", url).Start()
+	}
+}
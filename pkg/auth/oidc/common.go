@@ -18,8 +18,14 @@ package oidc
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/Peripli/service-manager-cli/internal/util"
 	"github.com/Peripli/service-manager-cli/pkg/auth"
@@ -65,6 +71,7 @@ func NewClient(options *auth.Options, token *auth.Token) auth.Client {
 	return &Client{
 		tokenSource: tokenSource,
 		httpClient:  oauthClient,
+		options:     options,
 	}
 }
 
@@ -97,6 +104,13 @@ func clientCredentialsTokenSource(ctx context.Context, options *auth.Options, to
 type Client struct {
 	tokenSource oauth2.TokenSource
 	httpClient  *http.Client
+	options     *auth.Options
+
+	verifierMutex sync.Mutex
+	verifier      *Verifier
+
+	claimsMutex sync.RWMutex
+	claims      *IDToken
 }
 
 // Do makes a http request with the underlying HTTP client which includes an access token in the request
@@ -104,12 +118,26 @@ func (c *Client) Do(req *http.Request) (*http.Response, error) {
 	return c.httpClient.Do(req)
 }
 
-// Token returns the token, refreshing it if necessary
+// Token returns the token, refreshing it if necessary. Whenever the underlying
+// token carries an id_token, it is verified against the issuer's JWKS and the
+// resulting claims are cached for retrieval via Claims(). Verification itself
+// (signature, issuer, audience, expiry) is never skipped, but it is best-effort:
+// the access token is already trusted by virtue of coming from the token
+// endpoint over TLS, so a failure to verify the id_token (e.g. the issuer's
+// JWKS being briefly unreachable) is logged rather than failing every request
+// until it resolves.
 func (c *Client) Token() (*auth.Token, error) {
 	token, err := c.tokenSource.Token()
 	if err != nil {
 		return nil, err
 	}
+
+	if rawIDToken, ok := token.Extra("id_token").(string); ok && rawIDToken != "" {
+		if err := c.verifyAndCacheClaims(rawIDToken); err != nil {
+			fmt.Printf("could not verify id token, proceeding without refreshed claims: %v\n", err)
+		}
+	}
+
 	return &auth.Token{
 		AccessToken:  token.AccessToken,
 		RefreshToken: token.RefreshToken,
@@ -118,9 +146,106 @@ func (c *Client) Token() (*auth.Token, error) {
 	}, nil
 }
 
+// Claims returns the claims of the last verified ID token, or nil if no ID token
+// has been verified yet.
+func (c *Client) Claims() *IDToken {
+	c.claimsMutex.RLock()
+	defer c.claimsMutex.RUnlock()
+	return c.claims
+}
+
+func (c *Client) verifyAndCacheClaims(rawIDToken string) error {
+	verifier, err := c.getVerifier()
+	if err != nil {
+		return err
+	}
+
+	idToken, err := verifier.Verify(context.Background(), rawIDToken)
+	if err != nil {
+		return fmt.Errorf("could not verify id token: %v", err)
+	}
+
+	c.claimsMutex.Lock()
+	c.claims = idToken
+	c.claimsMutex.Unlock()
+
+	return nil
+}
+
+// getVerifier lazily builds the Verifier from the issuer's openid-configuration and
+// JWKS, retrying that setup on every call until it succeeds, since a one-shot
+// sync.Once would otherwise leave the verifier permanently nil after a transient
+// setup failure (e.g. the issuer being briefly unreachable).
+func (c *Client) getVerifier() (*Verifier, error) {
+	c.verifierMutex.Lock()
+	defer c.verifierMutex.Unlock()
+
+	if c.verifier != nil {
+		return c.verifier, nil
+	}
+
+	httpClient := util.BuildHTTPClient(c.options.SSLDisabled)
+	configuration, err := fetchOpenidConfiguration(c.options.IssuerURL, httpClient.Do)
+	if err != nil {
+		return nil, err
+	}
+	keySet := NewKeySet(configuration.JwksURI, httpClient.Do)
+	c.verifier = NewVerifier(keySet, VerifierConfig{IssuerURL: c.options.IssuerURL, ClientID: c.options.ClientID})
+
+	return c.verifier, nil
+}
+
 // DoRequestFunc is an alias for any function that takes an http request and returns a response and error
 type DoRequestFunc func(request *http.Request) (*http.Response, error)
 
+// openIDConfiguration is the subset of the issuer's /.well-known/openid-configuration
+// document that this client needs.
+type openIDConfiguration struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+// postTokenRequest POSTs form to options.TokenEndpoint and decodes the resulting
+// access + refresh token. It is shared by every grant type in this package
+// (authorization_code, password) that exchanges a form at the token endpoint.
+func postTokenRequest(options *auth.Options, form url.Values) (*auth.Token, error) {
+	req, err := http.NewRequest(http.MethodPost, options.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	httpClient := util.BuildHTTPClient(options.SSLDisabled)
+	response, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned unexpected status code %d", response.StatusCode)
+	}
+
+	var tokenResponse struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		TokenType    string `json:"token_type"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&tokenResponse); err != nil {
+		return nil, err
+	}
+
+	return &auth.Token{
+		AccessToken:  tokenResponse.AccessToken,
+		RefreshToken: tokenResponse.RefreshToken,
+		TokenType:    tokenResponse.TokenType,
+		ExpiresIn:    time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second),
+	}, nil
+}
+
 func fetchOpenidConfiguration(issuerURL string, readConfigurationFunc DoRequestFunc) (*openIDConfiguration, error) {
 	req, err := http.NewRequest(http.MethodGet, issuerURL+"/.well-known/openid-configuration", nil)
 	if err != nil {
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package oidc
+
+import (
+	"testing"
+
+	"github.com/Peripli/service-manager-cli/pkg/auth"
+)
+
+// TestGetVerifierRetriesAfterSetupFailure proves that a transient failure to fetch
+// the openid-configuration/JWKS does not permanently wedge the client into calling
+// Verify on a nil verifier on every later attempt: the verifier stays nil (rather
+// than a half-initialized, panic-prone value) and a later call is free to retry.
+func TestGetVerifierRetriesAfterSetupFailure(t *testing.T) {
+	// Nothing listens on this port, so the fetch fails immediately with a
+	// connection error instead of hitting the network or a DNS timeout.
+	client := &Client{options: &auth.Options{IssuerURL: "http://127.0.0.1:1"}}
+
+	if _, err := client.getVerifier(); err == nil {
+		t.Fatal("expected getVerifier to surface the setup failure")
+	}
+	if client.verifier != nil {
+		t.Fatal("expected verifier to stay nil after a failed setup")
+	}
+
+	// A second call must attempt setup again rather than reusing a cached nil
+	// verifier and panicking on verifier.Verify.
+	if _, err := client.getVerifier(); err == nil {
+		t.Fatal("expected getVerifier to retry and fail again")
+	}
+	if client.verifier != nil {
+		t.Fatal("expected verifier to still be nil after a second failed setup")
+	}
+}
+
+// TestGetVerifierReusesSuccessfulSetup proves a verifier built by a prior call is
+// reused rather than rebuilt on every Token() call.
+func TestGetVerifierReusesSuccessfulSetup(t *testing.T) {
+	preset := NewVerifier(newStaticKeySet(&JSONWebKey{Kid: "kid-1", Kty: "RSA"}), VerifierConfig{ClientID: testClientID})
+	client := &Client{options: &auth.Options{IssuerURL: "http://127.0.0.1:1"}, verifier: preset}
+
+	verifier, err := client.getVerifier()
+	if err != nil {
+		t.Fatalf("expected cached verifier to be returned without error, got: %v", err)
+	}
+	if verifier != preset {
+		t.Fatal("expected getVerifier to return the already-initialized verifier")
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Peripli/service-manager-cli/pkg/httputil"
+)
+
+// defaultKeySetTTL is how long a fetched JWKS document is trusted before it is
+// refreshed again, even if every kid seen so far is still present in the cache.
+const defaultKeySetTTL = 1 * time.Hour
+
+// JSONWebKey is a single key of a JWKS document, as returned by an issuer's jwks_uri.
+type JSONWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+
+	// RSA public key parameters
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC public key parameters
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwksDocument struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// KeySet fetches the JWKS document exposed by an issuer's jwks_uri and caches its
+// keys by kid, transparently refetching on a kid miss or once the cached copy's
+// TTL has elapsed.
+type KeySet struct {
+	jwksURI       string
+	doRequestFunc DoRequestFunc
+	ttl           time.Duration
+
+	mutex     sync.Mutex
+	keys      map[string]JSONWebKey
+	fetchedAt time.Time
+}
+
+// NewKeySet returns a KeySet that fetches keys from jwksURI using doRequestFunc.
+func NewKeySet(jwksURI string, doRequestFunc DoRequestFunc) *KeySet {
+	return &KeySet{
+		jwksURI:       jwksURI,
+		doRequestFunc: doRequestFunc,
+		ttl:           defaultKeySetTTL,
+	}
+}
+
+// Key returns the JWK with the given kid, refreshing the underlying JWKS document
+// if the key is not already cached or the cache has expired.
+func (ks *KeySet) Key(ctx context.Context, kid string) (*JSONWebKey, error) {
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+
+	if key, ok := ks.lookupLocked(kid); ok {
+		return key, nil
+	}
+
+	if err := ks.refreshLocked(); err != nil {
+		return nil, err
+	}
+
+	key, ok := ks.lookupLocked(kid)
+	if !ok {
+		return nil, fmt.Errorf("no JWK found for kid %q", kid)
+	}
+	return key, nil
+}
+
+func (ks *KeySet) lookupLocked(kid string) (*JSONWebKey, bool) {
+	if ks.keys == nil || time.Since(ks.fetchedAt) >= ks.ttl {
+		return nil, false
+	}
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, false
+	}
+	return &key, true
+}
+
+func (ks *KeySet) refreshLocked() error {
+	req, err := http.NewRequest(http.MethodGet, ks.jwksURI, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := ks.doRequestFunc(req)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks endpoint returned unexpected status code %d", response.StatusCode)
+	}
+
+	var document jwksDocument
+	if err := httputil.UnmarshalResponse(response, &document); err != nil {
+		return err
+	}
+
+	keys := make(map[string]JSONWebKey, len(document.Keys))
+	for _, key := range document.Keys {
+		keys[key.Kid] = key
+	}
+
+	ks.keys = keys
+	ks.fetchedAt = time.Now()
+
+	return nil
+}
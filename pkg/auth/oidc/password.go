@@ -0,0 +1,81 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package oidc
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/Peripli/service-manager-cli/internal/util"
+	"github.com/Peripli/service-manager-cli/pkg/auth"
+)
+
+// StrategyPassword is the name under which the resource owner password
+// credentials (ROPC) grant is known to configuration and the authentication
+// strategy registry.
+const StrategyPassword = "password"
+
+func init() {
+	auth.RegisterStrategy(StrategyPassword, func(options *auth.Options) (auth.Authenticator, error) {
+		return NewPasswordStrategy(options)
+	})
+}
+
+// PasswordStrategy implements auth.Authenticator by exchanging a username and
+// password directly for a token at the issuer's token_endpoint (RFC 6749 ยง4.3).
+// It exists for non-interactive environments that cannot open a browser for the
+// authorization_code flow but still authenticate against the configured IdP
+// rather than SM's own basic auth.
+type PasswordStrategy struct {
+	options *auth.Options
+}
+
+// NewPasswordStrategy returns a PasswordStrategy for the given options, resolving
+// the token endpoint from the issuer's openid-configuration document if it has
+// not already been populated.
+func NewPasswordStrategy(options *auth.Options) (auth.Authenticator, error) {
+	if options.TokenEndpoint == "" {
+		httpClient := util.BuildHTTPClient(options.SSLDisabled)
+		configuration, err := fetchOpenidConfiguration(options.IssuerURL, httpClient.Do)
+		if err != nil {
+			return nil, err
+		}
+		options.TokenEndpoint = configuration.TokenEndpoint
+	}
+
+	return &PasswordStrategy{options: options}, nil
+}
+
+// Authenticate posts the configured username and password to the token endpoint
+// using the password grant type and returns the resulting token.
+func (s *PasswordStrategy) Authenticate() (*auth.Token, error) {
+	if s.options.User == "" {
+		return nil, errors.New("password auth strategy requires a user")
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "password")
+	form.Set("username", s.options.User)
+	form.Set("password", s.options.Password)
+	form.Set("scope", "openid offline_access")
+	form.Set("client_id", s.options.ClientID)
+	if s.options.ClientSecret != "" {
+		form.Set("client_secret", s.options.ClientSecret)
+	}
+
+	return postTokenRequest(s.options, form)
+}
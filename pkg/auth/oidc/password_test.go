@@ -0,0 +1,95 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package oidc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Peripli/service-manager-cli/pkg/auth"
+)
+
+func TestPasswordStrategyAuthenticate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("could not parse token request form: %v", err)
+		}
+		if r.FormValue("grant_type") != "password" {
+			t.Fatalf("expected grant_type=password, got %q", r.FormValue("grant_type"))
+		}
+		if r.FormValue("username") != "jdoe" {
+			t.Fatalf("expected username=jdoe, got %q", r.FormValue("username"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"password-token","refresh_token":"password-refresh","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	strategy := &PasswordStrategy{options: &auth.Options{
+		User:          "jdoe",
+		Password:      "s3cret",
+		TokenEndpoint: server.URL,
+	}}
+
+	token, err := strategy.Authenticate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.AccessToken != "password-token" {
+		t.Fatalf("expected access token %q, got %q", "password-token", token.AccessToken)
+	}
+	if token.RefreshToken != "password-refresh" {
+		t.Fatalf("expected refresh token %q, got %q", "password-refresh", token.RefreshToken)
+	}
+}
+
+func TestPasswordStrategyAuthenticateRequiresUser(t *testing.T) {
+	strategy := &PasswordStrategy{options: &auth.Options{}}
+
+	if _, err := strategy.Authenticate(); err == nil {
+		t.Fatal("expected an error when no user is configured")
+	}
+}
+
+func TestHasRefreshingClient(t *testing.T) {
+	withRefresh := &auth.Token{RefreshToken: "a-refresh-token"}
+	withoutRefresh := &auth.Token{}
+
+	cases := []struct {
+		strategy string
+		token    *auth.Token
+		expected bool
+	}{
+		{StrategyName, withoutRefresh, true},
+		{StrategyName, withRefresh, true},
+		{StrategyAuthorizationCode, withRefresh, true},
+		{StrategyAuthorizationCode, withoutRefresh, false},
+		{StrategyPassword, withRefresh, true},
+		{StrategyPassword, withoutRefresh, false},
+		{"basic", withRefresh, false},
+		{"static-token", withRefresh, false},
+		{"exec", withRefresh, false},
+	}
+
+	for _, c := range cases {
+		if actual := HasRefreshingClient(c.strategy, c.token); actual != c.expected {
+			t.Errorf("HasRefreshingClient(%q, %+v) = %v, expected %v", c.strategy, c.token, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package oidc
+
+import "github.com/Peripli/service-manager-cli/pkg/auth"
+
+// StrategyName is the name oidc registers itself under, and the default value
+// of ClientConfig.AuthStrategy.
+const StrategyName = "oidc"
+
+func init() {
+	auth.RegisterStrategy(StrategyName, func(options *auth.Options) (auth.Authenticator, error) {
+		authenticator, _, err := NewOpenIDStrategy(options)
+		return authenticator, err
+	})
+}
+
+// HasRefreshingClient reports whether token should be wrapped in NewClient's
+// refreshing TokenSource rather than auth.NewGenericClient. The oidc strategy
+// always qualifies: its own reauthentication fallback *is* the client_credentials
+// grant NewClient falls back to when there is no refresh_token. authorization_code
+// and password only qualify when token actually carries a refresh_token: without
+// one, NewClient would silently reauthenticate via client_credentials using the
+// client's own secret instead of rerunning their (browser/password) Authenticate
+// flow, which is what auth.NewGenericClient correctly does instead.
+func HasRefreshingClient(strategyName string, token *auth.Token) bool {
+	switch strategyName {
+	case StrategyName:
+		return true
+	case StrategyAuthorizationCode, StrategyPassword:
+		return token != nil && token.RefreshToken != ""
+	default:
+		return false
+	}
+}
@@ -0,0 +1,288 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// defaultClockSkewLeeway is the default tolerance applied when validating the
+// exp/iat/nbf claims of an ID token.
+const defaultClockSkewLeeway = 1 * time.Minute
+
+// VerifierConfig configures an ID token Verifier.
+type VerifierConfig struct {
+	// IssuerURL must equal the token's iss claim exactly.
+	IssuerURL string
+	// ClientID must appear in the token's aud claim.
+	ClientID string
+	// SkewLeeway is the clock skew tolerance applied to exp/iat/nbf. Defaults to
+	// defaultClockSkewLeeway when zero.
+	SkewLeeway time.Duration
+}
+
+// IDToken holds the claims of a verified OIDC ID token.
+type IDToken struct {
+	Issuer   string
+	Subject  string
+	Audience []string
+	IssuedAt time.Time
+	Expiry   time.Time
+	Email    string
+	Groups   []string
+}
+
+type idTokenClaims struct {
+	Issuer    string          `json:"iss"`
+	Subject   string          `json:"sub"`
+	Audience  json.RawMessage `json:"aud"`
+	Expiry    int64           `json:"exp"`
+	IssuedAt  int64           `json:"iat"`
+	NotBefore int64           `json:"nbf"`
+	Email     string          `json:"email"`
+	Groups    []string        `json:"groups"`
+}
+
+// Verifier validates the signature and standard claims of OIDC ID tokens, fetching
+// signing keys from a KeySet as needed.
+type Verifier struct {
+	keySet *KeySet
+	config VerifierConfig
+}
+
+// NewVerifier returns a Verifier that checks signatures against keySet and validates
+// claims according to config.
+func NewVerifier(keySet *KeySet, config VerifierConfig) *Verifier {
+	if config.SkewLeeway == 0 {
+		config.SkewLeeway = defaultClockSkewLeeway
+	}
+	return &Verifier{keySet: keySet, config: config}
+}
+
+// Verify checks the signature of rawIDToken against the issuer's JWKS and validates
+// its iss, aud, exp, iat and nbf claims, returning the parsed claims on success.
+func (v *Verifier) Verify(ctx context.Context, rawIDToken string) (*IDToken, error) {
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("id token is not a valid JWT")
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("could not decode id token header: %v", err)
+	}
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("could not decode id token payload: %v", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("could not decode id token signature: %v", err)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, fmt.Errorf("could not parse id token header: %v", err)
+	}
+
+	key, err := v.keySet.Key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signingInput := parts[0] + "." + parts[1]
+	if err := verifySignature(header.Alg, key, []byte(signingInput), signature); err != nil {
+		return nil, err
+	}
+
+	var claims idTokenClaims
+	if err := json.Unmarshal(payloadBytes, &claims); err != nil {
+		return nil, fmt.Errorf("could not parse id token claims: %v", err)
+	}
+
+	audience, err := decodeAudience(claims.Audience)
+	if err != nil {
+		return nil, err
+	}
+
+	idToken := &IDToken{
+		Issuer:   claims.Issuer,
+		Subject:  claims.Subject,
+		Audience: audience,
+		IssuedAt: time.Unix(claims.IssuedAt, 0),
+		Expiry:   time.Unix(claims.Expiry, 0),
+		Email:    claims.Email,
+		Groups:   claims.Groups,
+	}
+
+	if err := v.validateClaims(idToken, claims.NotBefore); err != nil {
+		return nil, err
+	}
+
+	return idToken, nil
+}
+
+func (v *Verifier) validateClaims(idToken *IDToken, notBefore int64) error {
+	now := time.Now()
+
+	if idToken.Issuer != v.config.IssuerURL {
+		return fmt.Errorf("id token issuer %q does not match expected issuer %q", idToken.Issuer, v.config.IssuerURL)
+	}
+
+	found := false
+	for _, aud := range idToken.Audience {
+		if aud == v.config.ClientID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("id token audience %v does not contain client id %q", idToken.Audience, v.config.ClientID)
+	}
+
+	if now.After(idToken.Expiry.Add(v.config.SkewLeeway)) {
+		return fmt.Errorf("id token is expired since %s", idToken.Expiry)
+	}
+
+	if now.Before(idToken.IssuedAt.Add(-v.config.SkewLeeway)) {
+		return fmt.Errorf("id token was issued in the future: %s", idToken.IssuedAt)
+	}
+
+	if notBefore != 0 {
+		nbf := time.Unix(notBefore, 0)
+		if now.Before(nbf.Add(-v.config.SkewLeeway)) {
+			return fmt.Errorf("id token is not valid before %s", nbf)
+		}
+	}
+
+	return nil
+}
+
+func decodeAudience(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}, nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(raw, &multiple); err == nil {
+		return multiple, nil
+	}
+
+	return nil, errors.New("id token aud claim has an unsupported shape")
+}
+
+// verifySignature checks signature against the key fetched for the token's kid.
+// It only accepts asymmetric algorithms whose family matches the key's own kty,
+// so a token cannot pick its own verification algorithm (e.g. a forged "alg":
+// "HS256" header cannot turn a public RSA/EC key into an HMAC secret).
+func verifySignature(alg string, key *JSONWebKey, signingInput, signature []byte) error {
+	switch alg {
+	case "RS256":
+		if key.Kty != "RSA" {
+			return fmt.Errorf("id token alg %q does not match key type %q", alg, key.Kty)
+		}
+		publicKey, err := rsaPublicKey(key)
+		if err != nil {
+			return err
+		}
+		digest := sha256.Sum256(signingInput)
+		if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature); err != nil {
+			return fmt.Errorf("rs256 signature verification failed: %v", err)
+		}
+		return nil
+	case "ES256":
+		if key.Kty != "EC" {
+			return fmt.Errorf("id token alg %q does not match key type %q", alg, key.Kty)
+		}
+		publicKey, err := ecPublicKey(key)
+		if err != nil {
+			return err
+		}
+		if len(signature) != 64 {
+			return errors.New("es256 signature has unexpected length")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		digest := sha256.Sum256(signingInput)
+		if !ecdsa.Verify(publicKey, digest[:], r, s) {
+			return errors.New("es256 signature verification failed")
+		}
+		return nil
+	default:
+		// Symmetric algorithms (e.g. HS256) are deliberately not supported here:
+		// keys come from a publicly readable JWKS endpoint, so any secret a token
+		// could present would be public too, making HMAC verification forgeable.
+		return fmt.Errorf("unsupported id token signing algorithm %q", alg)
+	}
+}
+
+func rsaPublicKey(key *JSONWebKey) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode rsa modulus: %v", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode rsa exponent: %v", err)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(new(big.Int).SetBytes(eBytes).Int64()),
+	}, nil
+}
+
+func ecPublicKey(key *JSONWebKey) (*ecdsa.PublicKey, error) {
+	if key.Crv != "P-256" {
+		return nil, fmt.Errorf("unsupported ec curve %q", key.Crv)
+	}
+
+	xBytes, err := base64.RawURLEncoding.DecodeString(key.X)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode ec x coordinate: %v", err)
+	}
+	yBytes, err := base64.RawURLEncoding.DecodeString(key.Y)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode ec y coordinate: %v", err)
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(xBytes),
+		Y:     new(big.Int).SetBytes(yBytes),
+	}, nil
+}
@@ -0,0 +1,248 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testClientID = "test-client"
+const testIssuerURL = "https://issuer.example.com"
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, signingInput string) []byte {
+	t.Helper()
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("could not sign test token: %v", err)
+	}
+	return signature
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, signingInput string) []byte {
+	t.Helper()
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("could not sign test token: %v", err)
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return signature
+}
+
+func rawToken(t *testing.T, alg, kid string, claims map[string]interface{}, signature []byte) string {
+	t.Helper()
+
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid})
+	if err != nil {
+		t.Fatalf("could not marshal test header: %v", err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("could not marshal test claims: %v", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func defaultClaims() map[string]interface{} {
+	now := time.Now()
+	return map[string]interface{}{
+		"iss": testIssuerURL,
+		"sub": "user-1",
+		"aud": testClientID,
+		"exp": now.Add(time.Hour).Unix(),
+		"iat": now.Unix(),
+	}
+}
+
+func TestVerifyRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate rsa key: %v", err)
+	}
+
+	jwk := &JSONWebKey{
+		Kid: "kid-1",
+		Kty: "RSA",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+
+	verifier := NewVerifier(newStaticKeySet(jwk), VerifierConfig{IssuerURL: testIssuerURL, ClientID: testClientID})
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": jwk.Kid})
+	payload, _ := json.Marshal(defaultClaims())
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature := signRS256(t, key, signingInput)
+
+	token := signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+
+	if _, err := verifier.Verify(context.Background(), token); err != nil {
+		t.Fatalf("expected valid rs256 token to verify, got: %v", err)
+	}
+}
+
+func TestVerifyES256(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate ec key: %v", err)
+	}
+
+	jwk := &JSONWebKey{
+		Kid: "kid-1",
+		Kty: "EC",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(key.X.Bytes()),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.Bytes()),
+	}
+
+	verifier := NewVerifier(newStaticKeySet(jwk), VerifierConfig{IssuerURL: testIssuerURL, ClientID: testClientID})
+
+	header, _ := json.Marshal(map[string]string{"alg": "ES256", "kid": jwk.Kid})
+	payload, _ := json.Marshal(defaultClaims())
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature := signES256(t, key, signingInput)
+
+	token := signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+
+	if _, err := verifier.Verify(context.Background(), token); err != nil {
+		t.Fatalf("expected valid es256 token to verify, got: %v", err)
+	}
+}
+
+// TestVerifyRejectsAlgConfusion proves a token cannot force HMAC verification
+// against an RSA key sourced from the (publicly readable) JWKS document.
+func TestVerifyRejectsAlgConfusion(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate rsa key: %v", err)
+	}
+
+	jwk := &JSONWebKey{
+		Kid: "kid-1",
+		Kty: "RSA",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+
+	verifier := NewVerifier(newStaticKeySet(jwk), VerifierConfig{IssuerURL: testIssuerURL, ClientID: testClientID})
+
+	header, _ := json.Marshal(map[string]string{"alg": "HS256", "kid": jwk.Kid})
+	payload, _ := json.Marshal(defaultClaims())
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	// Forge an HMAC signature using the empty secret an attacker would derive
+	// from the RSA key's (absent) "k" field.
+	mac := hmac.New(sha256.New, nil)
+	mac.Write([]byte(signingInput))
+	forgedSignature := mac.Sum(nil)
+
+	token := signingInput + "." + base64.RawURLEncoding.EncodeToString(forgedSignature)
+
+	if _, err := verifier.Verify(context.Background(), token); err == nil {
+		t.Fatal("expected forged hs256 token to be rejected, but it verified")
+	}
+}
+
+func TestVerifyRejectsMismatchedAlgForKeyType(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate rsa key: %v", err)
+	}
+
+	jwk := &JSONWebKey{
+		Kid: "kid-1",
+		Kty: "RSA",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+
+	verifier := NewVerifier(newStaticKeySet(jwk), VerifierConfig{IssuerURL: testIssuerURL, ClientID: testClientID})
+
+	header, _ := json.Marshal(map[string]string{"alg": "ES256", "kid": jwk.Kid})
+	payload, _ := json.Marshal(defaultClaims())
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	token := signingInput + "." + base64.RawURLEncoding.EncodeToString(make([]byte, 64))
+
+	_, err = verifier.Verify(context.Background(), token)
+	if err == nil || !strings.Contains(err.Error(), "does not match key type") {
+		t.Fatalf("expected alg/key type mismatch error, got: %v", err)
+	}
+}
+
+// TestVerifyRejectsIssuerMismatch proves a token that is otherwise validly signed
+// is still rejected when its iss claim does not match the configured issuer, so a
+// token from a different (but still discoverable) issuer cannot be replayed.
+func TestVerifyRejectsIssuerMismatch(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate rsa key: %v", err)
+	}
+
+	jwk := &JSONWebKey{
+		Kid: "kid-1",
+		Kty: "RSA",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+
+	verifier := NewVerifier(newStaticKeySet(jwk), VerifierConfig{IssuerURL: testIssuerURL, ClientID: testClientID})
+
+	claims := defaultClaims()
+	claims["iss"] = "https://attacker.example.com"
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": jwk.Kid})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature := signRS256(t, key, signingInput)
+
+	token := signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+
+	_, err = verifier.Verify(context.Background(), token)
+	if err == nil || !strings.Contains(err.Error(), "does not match expected issuer") {
+		t.Fatalf("expected issuer mismatch error, got: %v", err)
+	}
+}
+
+// newStaticKeySet returns a KeySet pre-populated with a single key, so tests don't
+// need to exercise the network-fetching refreshLocked path.
+func newStaticKeySet(key *JSONWebKey) *KeySet {
+	return &KeySet{
+		keys:      map[string]JSONWebKey{key.Kid: *key},
+		fetchedAt: time.Now(),
+		ttl:       time.Hour,
+	}
+}
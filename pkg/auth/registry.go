@@ -0,0 +1,43 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package auth
+
+import "fmt"
+
+// StrategyFactory builds an Authenticator from the given options. Implementations
+// register themselves under a name via RegisterStrategy, typically from their own
+// package's init() function.
+type StrategyFactory func(*Options) (Authenticator, error)
+
+var strategies = map[string]StrategyFactory{}
+
+// RegisterStrategy makes a StrategyFactory available under name, so it can later
+// be selected by ClientConfig.AuthStrategy without smclient having to import the
+// strategy's package directly. This mirrors how kubernetes client-go loads auth
+// plugins by name. Registering a name a second time replaces the previous factory.
+func RegisterStrategy(name string, factory StrategyFactory) {
+	strategies[name] = factory
+}
+
+// GetStrategy looks up the StrategyFactory registered under name.
+func GetStrategy(name string) (StrategyFactory, error) {
+	factory, ok := strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("no authentication strategy registered for %q", name)
+	}
+	return factory, nil
+}
@@ -0,0 +1,35 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package auth
+
+import "testing"
+
+func TestRegisterAndGetStrategy(t *testing.T) {
+	RegisterStrategy("test-strategy", func(options *Options) (Authenticator, error) {
+		return nil, nil
+	})
+
+	if _, err := GetStrategy("test-strategy"); err != nil {
+		t.Fatalf("expected registered strategy to be found, got: %v", err)
+	}
+}
+
+func TestGetStrategyUnknownName(t *testing.T) {
+	if _, err := GetStrategy("does-not-exist"); err == nil {
+		t.Fatal("expected an error for an unregistered strategy name")
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package statictoken implements an auth strategy that uses a pre-issued bearer
+// token, sourced from an env var or file, with no refresh logic.
+package statictoken
+
+import (
+	"errors"
+
+	"github.com/Peripli/service-manager-cli/pkg/auth"
+)
+
+// StrategyName is the name this strategy registers itself under.
+const StrategyName = "static-token"
+
+func init() {
+	auth.RegisterStrategy(StrategyName, func(options *auth.Options) (auth.Authenticator, error) {
+		return New(options), nil
+	})
+}
+
+// Strategy attaches a pre-issued, never-refreshed bearer token to every request.
+type Strategy struct {
+	options *auth.Options
+}
+
+// New returns a static-token Strategy for the given options.
+func New(options *auth.Options) *Strategy {
+	return &Strategy{options: options}
+}
+
+// Authenticate returns the configured token as-is; there is nothing to refresh.
+func (s *Strategy) Authenticate() (*auth.Token, error) {
+	if s.options.Token == "" {
+		return nil, errors.New("static-token auth strategy requires a token")
+	}
+
+	return &auth.Token{
+		AccessToken: s.options.Token,
+		TokenType:   "Bearer",
+	}, nil
+}
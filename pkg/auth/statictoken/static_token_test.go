@@ -0,0 +1,46 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package statictoken
+
+import (
+	"testing"
+
+	"github.com/Peripli/service-manager-cli/pkg/auth"
+)
+
+func TestAuthenticate(t *testing.T) {
+	strategy := New(&auth.Options{Token: "my-token"})
+
+	token, err := strategy.Authenticate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.AccessToken != "my-token" {
+		t.Fatalf("expected access token %q, got %q", "my-token", token.AccessToken)
+	}
+	if token.TokenType != "Bearer" {
+		t.Fatalf("expected token type Bearer, got %q", token.TokenType)
+	}
+}
+
+func TestAuthenticateRequiresToken(t *testing.T) {
+	strategy := New(&auth.Options{})
+
+	if _, err := strategy.Authenticate(); err == nil {
+		t.Fatal("expected an error when no token is configured")
+	}
+}
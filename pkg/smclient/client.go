@@ -18,23 +18,33 @@ package smclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"path"
 
 	"github.com/Peripli/service-manager/pkg/web"
 
 	"github.com/Peripli/service-manager-cli/pkg/auth/oidc"
 
+	"github.com/Peripli/service-manager-cli/internal/util"
 	"github.com/Peripli/service-manager-cli/pkg/auth"
 	"github.com/Peripli/service-manager-cli/pkg/errors"
 	"github.com/Peripli/service-manager-cli/pkg/httputil"
 	"github.com/Peripli/service-manager-cli/pkg/query"
 	"github.com/Peripli/service-manager-cli/pkg/types"
+
+	// Blank-imported so their init() registers each strategy with the auth
+	// registry; NewClientWithAuth resolves them by name via ClientConfig.AuthStrategy.
+	_ "github.com/Peripli/service-manager-cli/pkg/auth/basic"
+	_ "github.com/Peripli/service-manager-cli/pkg/auth/exec"
+	_ "github.com/Peripli/service-manager-cli/pkg/auth/statictoken"
 )
 
 // Client should be implemented by SM clients
+//
 //go:generate counterfeiter . Client
 type Client interface {
 	GetInfo(*query.Parameters) (*types.Info, error)
@@ -55,9 +65,24 @@ type Client interface {
 	DeleteVisibilities(*query.Parameters) error
 
 	ListOfferings(*query.Parameters) (*types.ServiceOfferings, error)
+	OfferingsIterator(*query.Parameters) *OfferingsIterator
+
+	Provision(*types.ServiceInstance, *query.Parameters) (*types.ServiceInstance, error)
+	ListInstances(*query.Parameters) (*types.ServiceInstances, error)
+	UpdateInstance(string, *types.ServiceInstance, *query.Parameters) (*types.ServiceInstance, error)
+	Deprovision(string, *query.Parameters) error
+	GetInstanceParameters(string, *query.Parameters) (map[string]interface{}, error)
+
+	Bind(*types.ServiceBinding, *query.Parameters) (*types.ServiceBinding, error)
+	ListBindings(*query.Parameters) (*types.ServiceBindings, error)
+	Unbind(string, *query.Parameters) error
+	GetBindingCredentials(string, *query.Parameters) (map[string]interface{}, error)
 
 	Label(string, string, *types.LabelChanges, *query.Parameters) error
 
+	GetOperation(resourceURL, opID string) (*types.Operation, error)
+	Poll(ctx context.Context, op *types.Operation, opts PollOptions) (*types.Operation, error)
+
 	// Call makes HTTP request to the Service Manager server with authentication.
 	// It should be used only in case there is no already implemented method for such an operation
 	Call(method string, smpath string, body io.Reader, q *query.Parameters) (*http.Response, error)
@@ -85,9 +110,18 @@ func NewClientWithAuth(httpClient auth.Client, config *ClientConfig) (Client, er
 		ClientSecret: config.ClientSecret,
 		SSLDisabled:  config.SSLDisabled,
 	}
-	var authStrategy auth.Authenticator
-	authStrategy, authOptions, err = oidc.NewOpenIDStrategy(authOptions)
 
+	strategyName := config.AuthStrategy
+	if strategyName == "" {
+		strategyName = oidc.StrategyName
+	}
+
+	strategyFactory, err := auth.GetStrategy(strategyName)
+	if err != nil {
+		return nil, err
+	}
+
+	authStrategy, err := strategyFactory(authOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +130,14 @@ func NewClientWithAuth(httpClient auth.Client, config *ClientConfig) (Client, er
 	if err != nil {
 		return nil, err
 	}
-	authClient := oidc.NewClient(authOptions, token)
+
+	var authClient auth.Client
+	if oidc.HasRefreshingClient(strategyName, token) {
+		authClient = oidc.NewClient(authOptions, token)
+	} else {
+		authClient = auth.NewGenericClient(authStrategy, util.BuildHTTPClient(authOptions.SSLDisabled))
+	}
+
 	client = &serviceManagerClient{config: config, httpClient: authClient}
 
 	return client, nil
@@ -129,7 +170,7 @@ func (client *serviceManagerClient) GetInfo(q *query.Parameters) (*types.Info, e
 // RegisterPlatform registers a platform in the service manager
 func (client *serviceManagerClient) RegisterPlatform(platform *types.Platform, q *query.Parameters) (*types.Platform, error) {
 	var newPlatform *types.Platform
-	err := client.register(platform, web.PlatformsURL, q, &newPlatform)
+	_, err := client.register(platform, web.PlatformsURL, q, &newPlatform, false)
 	if err != nil {
 		return nil, err
 	}
@@ -139,7 +180,7 @@ func (client *serviceManagerClient) RegisterPlatform(platform *types.Platform, q
 // RegisterBroker registers a broker in the service manager
 func (client *serviceManagerClient) RegisterBroker(broker *types.Broker, q *query.Parameters) (*types.Broker, error) {
 	var newBroker *types.Broker
-	err := client.register(broker, web.ServiceBrokersURL, q, &newBroker)
+	_, err := client.register(broker, web.ServiceBrokersURL, q, &newBroker, false)
 	if err != nil {
 		return nil, err
 	}
@@ -149,30 +190,105 @@ func (client *serviceManagerClient) RegisterBroker(broker *types.Broker, q *quer
 // RegisterVisibility registers a visibility in the service manager
 func (client *serviceManagerClient) RegisterVisibility(visibility *types.Visibility, q *query.Parameters) (*types.Visibility, error) {
 	var newVisibility *types.Visibility
-	err := client.register(visibility, web.VisibilitiesURL, q, &newVisibility)
+	_, err := client.register(visibility, web.VisibilitiesURL, q, &newVisibility, false)
 	if err != nil {
 		return nil, err
 	}
 	return newVisibility, nil
 }
 
-func (client *serviceManagerClient) register(resource interface{}, url string, q *query.Parameters, result interface{}) error {
+// register creates resource at url. When async is true, it asks Service Manager
+// to execute the operation asynchronously via ?async=true; regardless of async,
+// Service Manager may still answer synchronously with 201 and the created
+// resource, or with 202 and a Location header pointing at an asynchronous
+// operation, which is polled to completion before result is populated from the
+// final state of the resource. In the 202 case the operation itself is returned
+// so async-aware callers can inspect or keep polling it; a synchronous 201
+// returns a nil operation.
+func (client *serviceManagerClient) register(resource interface{}, url string, q *query.Parameters, result interface{}, async bool) (*types.Operation, error) {
 	requestBody, err := json.Marshal(resource)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if async {
+		q = withAsync(q)
 	}
 
 	buffer := bytes.NewBuffer(requestBody)
 	response, err := client.Call(http.MethodPost, url, buffer, q)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusAccepted {
+		return client.handleAsyncResponse(response, url, result, DefaultPollOptions())
 	}
 
 	if response.StatusCode != http.StatusCreated {
-		return errors.ResponseError{StatusCode: response.StatusCode}
+		return nil, errors.ResponseError{StatusCode: response.StatusCode}
 	}
 
-	return httputil.UnmarshalResponse(response, &result)
+	return nil, httputil.UnmarshalResponse(response, &result)
+}
+
+// handleAsyncResponse parses the operation out of a 202 Accepted response and,
+// when opts.Wait is set, polls it to completion and populates result from the
+// final resource state once the operation succeeds.
+func (client *serviceManagerClient) handleAsyncResponse(response *http.Response, resourceURL string, result interface{}, opts PollOptions) (*types.Operation, error) {
+	op, err := operationFromResponse(response)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.Wait {
+		return op, nil
+	}
+
+	op, err = client.Poll(context.Background(), op, opts)
+	if err != nil {
+		return op, err
+	}
+
+	if op.State == types.OperationStateFailed {
+		return op, operationError(op)
+	}
+
+	if result == nil || op.ResourceID == "" {
+		return op, nil
+	}
+
+	return op, client.list(result, resourceURL+"/"+op.ResourceID, nil)
+}
+
+// operationFromResponse parses the types.Operation carried in a 202 Accepted
+// response body, falling back to the Location header for the operation id if
+// the body does not include one. SM's documented 202 response may have an
+// empty body and rely entirely on the Location header, so an empty body is
+// tolerated rather than treated as a hard failure.
+func operationFromResponse(response *http.Response) (*types.Operation, error) {
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	response.Body.Close()
+
+	operation := &types.Operation{}
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, operation); err != nil {
+			return nil, err
+		}
+	}
+
+	if operation.ID == "" {
+		location := response.Header.Get("Location")
+		if location == "" {
+			return nil, fmt.Errorf("202 Accepted response did not include an operation id")
+		}
+		operation.ID = path.Base(location)
+	}
+
+	return operation, nil
 }
 
 // ListBrokers returns brokers registered in the Service Manager satisfying provided queries
@@ -197,37 +313,98 @@ func (client *serviceManagerClient) ListVisibilities(q *query.Parameters) (*type
 	return visibilities, err
 }
 
-// ListOfferings returns service offerings satisfying provided queries
+// ListOfferings returns service offerings satisfying provided queries, with their
+// plans and broker name filled in using a handful of batched requests rather than
+// two extra requests per offering.
 func (client *serviceManagerClient) ListOfferings(q *query.Parameters) (*types.ServiceOfferings, error) {
 	serviceOfferings := &types.ServiceOfferings{}
 	err := client.list(serviceOfferings, web.ServiceOfferingsURL, q)
 	if err != nil {
 		return nil, err
 	}
-	for i, so := range serviceOfferings.ServiceOfferings {
-		plans := &types.ServicePlans{}
-		err := client.list(plans, web.ServicePlansURL, &query.Parameters{
-			FieldQuery:    []string{fmt.Sprintf("service_offering_id = %s", so.ID)},
-			GeneralParams: q.GeneralParams,
-		})
-		if err != nil {
-			return nil, err
-		}
-		serviceOfferings.ServiceOfferings[i].Plans = plans.ServicePlans
 
-		broker := &types.Broker{}
-		err = client.list(broker, web.ServiceBrokersURL+"/"+so.BrokerID, &query.Parameters{
-			GeneralParams: q.GeneralParams,
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		serviceOfferings.ServiceOfferings[i].BrokerName = broker.Name
+	if err := client.enrichOfferings(serviceOfferings.ServiceOfferings, q); err != nil {
+		return nil, err
 	}
+
 	return serviceOfferings, nil
 }
 
+// Provision creates a service instance, waiting for any asynchronous provisioning
+// operation to complete before returning.
+func (client *serviceManagerClient) Provision(instance *types.ServiceInstance, q *query.Parameters) (*types.ServiceInstance, error) {
+	var newInstance *types.ServiceInstance
+	_, err := client.register(instance, web.ServiceInstancesURL, q, &newInstance, true)
+	if err != nil {
+		return nil, err
+	}
+	return newInstance, nil
+}
+
+// ListInstances returns service instances registered in Service Manager satisfying provided queries
+func (client *serviceManagerClient) ListInstances(q *query.Parameters) (*types.ServiceInstances, error) {
+	instances := &types.ServiceInstances{}
+	err := client.list(instances, web.ServiceInstancesURL, q)
+	return instances, err
+}
+
+// UpdateInstance updates a service instance, waiting for any asynchronous update
+// operation to complete before returning.
+func (client *serviceManagerClient) UpdateInstance(id string, updatedInstance *types.ServiceInstance, q *query.Parameters) (*types.ServiceInstance, error) {
+	result := &types.ServiceInstance{}
+	_, err := client.update(updatedInstance, web.ServiceInstancesURL, id, q, &result, true)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Deprovision deletes a service instance, waiting for any asynchronous
+// deprovisioning operation to complete before returning.
+func (client *serviceManagerClient) Deprovision(id string, q *query.Parameters) error {
+	return client.delete(web.ServiceInstancesURL+"/"+id, q, true)
+}
+
+// GetInstanceParameters returns the provisioning parameters of the service instance with the given id.
+func (client *serviceManagerClient) GetInstanceParameters(id string, q *query.Parameters) (map[string]interface{}, error) {
+	parameters := make(map[string]interface{})
+	err := client.list(&parameters, web.ServiceInstancesURL+"/"+id+"/parameters", q)
+	return parameters, err
+}
+
+// Bind creates a service binding, waiting for any asynchronous binding
+// operation to complete before returning.
+func (client *serviceManagerClient) Bind(binding *types.ServiceBinding, q *query.Parameters) (*types.ServiceBinding, error) {
+	var newBinding *types.ServiceBinding
+	_, err := client.register(binding, web.ServiceBindingsURL, q, &newBinding, true)
+	if err != nil {
+		return nil, err
+	}
+	return newBinding, nil
+}
+
+// ListBindings returns service bindings registered in Service Manager satisfying provided queries
+func (client *serviceManagerClient) ListBindings(q *query.Parameters) (*types.ServiceBindings, error) {
+	bindings := &types.ServiceBindings{}
+	err := client.list(bindings, web.ServiceBindingsURL, q)
+	return bindings, err
+}
+
+// Unbind deletes a service binding, waiting for any asynchronous unbinding
+// operation to complete before returning.
+func (client *serviceManagerClient) Unbind(id string, q *query.Parameters) error {
+	return client.delete(web.ServiceBindingsURL+"/"+id, q, true)
+}
+
+// GetBindingCredentials returns the credentials of the service binding with the given id.
+func (client *serviceManagerClient) GetBindingCredentials(id string, q *query.Parameters) (map[string]interface{}, error) {
+	binding := &types.ServiceBinding{}
+	if err := client.list(binding, web.ServiceBindingsURL+"/"+id, q); err != nil {
+		return nil, err
+	}
+	return binding.Credentials, nil
+}
+
 func (client *serviceManagerClient) list(result interface{}, url string, q *query.Parameters) error {
 	resp, err := client.Call(http.MethodGet, url, nil, q)
 	if err != nil {
@@ -242,38 +419,47 @@ func (client *serviceManagerClient) list(result interface{}, url string, q *quer
 }
 
 func (client *serviceManagerClient) DeleteBrokers(q *query.Parameters) error {
-	return client.delete(web.ServiceBrokersURL, q)
+	return client.delete(web.ServiceBrokersURL, q, false)
 }
 
 // DeleteBroker deletes a broker with given id from service manager
 func (client *serviceManagerClient) DeleteBroker(id string, q *query.Parameters) error {
-	return client.delete(web.ServiceBrokersURL+"/"+id, q)
+	return client.delete(web.ServiceBrokersURL+"/"+id, q, false)
 }
 
 func (client *serviceManagerClient) DeletePlatforms(q *query.Parameters) error {
-	return client.delete(web.PlatformsURL, q)
+	return client.delete(web.PlatformsURL, q, false)
 }
 
 // DeletePlatform deletes a platform with given id from service manager
 func (client *serviceManagerClient) DeletePlatform(id string, q *query.Parameters) error {
-	return client.delete(web.PlatformsURL+"/"+id, q)
+	return client.delete(web.PlatformsURL+"/"+id, q, false)
 }
 
 func (client *serviceManagerClient) DeleteVisibilities(q *query.Parameters) error {
-	return client.delete(web.VisibilitiesURL, q)
+	return client.delete(web.VisibilitiesURL, q, false)
 }
 
 // DeleteVisibility deletes a visibility with given id from service manager
 func (client *serviceManagerClient) DeleteVisibility(id string, q *query.Parameters) error {
-	return client.delete(web.VisibilitiesURL+"/"+id, q)
+	return client.delete(web.VisibilitiesURL+"/"+id, q, false)
 }
 
-func (client *serviceManagerClient) delete(url string, q *query.Parameters) error {
+func (client *serviceManagerClient) delete(url string, q *query.Parameters, async bool) error {
+	if async {
+		q = withAsync(q)
+	}
+
 	resp, err := client.Call(http.MethodDelete, url, nil, q)
 	if err != nil {
 		return err
 	}
 
+	if resp.StatusCode == http.StatusAccepted {
+		_, err := client.handleAsyncResponse(resp, url, nil, DefaultPollOptions())
+		return err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return errors.ResponseError{StatusCode: resp.StatusCode}
 	}
@@ -283,7 +469,7 @@ func (client *serviceManagerClient) delete(url string, q *query.Parameters) erro
 
 func (client *serviceManagerClient) UpdateBroker(id string, updatedBroker *types.Broker, q *query.Parameters) (*types.Broker, error) {
 	result := &types.Broker{}
-	err := client.update(updatedBroker, web.ServiceBrokersURL, id, q, &result)
+	_, err := client.update(updatedBroker, web.ServiceBrokersURL, id, q, &result, false)
 	if err != nil {
 		return nil, err
 	}
@@ -292,7 +478,7 @@ func (client *serviceManagerClient) UpdateBroker(id string, updatedBroker *types
 
 func (client *serviceManagerClient) UpdatePlatform(id string, updatedPlatform *types.Platform, q *query.Parameters) (*types.Platform, error) {
 	result := &types.Platform{}
-	err := client.update(updatedPlatform, web.PlatformsURL, id, q, &result)
+	_, err := client.update(updatedPlatform, web.PlatformsURL, id, q, &result, false)
 	if err != nil {
 		return nil, err
 	}
@@ -301,29 +487,42 @@ func (client *serviceManagerClient) UpdatePlatform(id string, updatedPlatform *t
 
 func (client *serviceManagerClient) UpdateVisibility(id string, updatedVisibility *types.Visibility, q *query.Parameters) (*types.Visibility, error) {
 	result := &types.Visibility{}
-	err := client.update(updatedVisibility, web.VisibilitiesURL, id, q, &result)
+	_, err := client.update(updatedVisibility, web.VisibilitiesURL, id, q, &result, false)
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (client *serviceManagerClient) update(resource interface{}, url string, id string, q *query.Parameters, result interface{}) error {
+// update is the PATCH counterpart of register: when async is true it asks
+// Service Manager to execute the operation asynchronously via ?async=true, but
+// either way it handles both the synchronous 200 response and the 202 Accepted
+// + Location async response the same way.
+func (client *serviceManagerClient) update(resource interface{}, url string, id string, q *query.Parameters, result interface{}, async bool) (*types.Operation, error) {
 	requestBody, err := json.Marshal(resource)
 	if err != nil {
-		return err
+		return nil, err
 	}
+
+	if async {
+		q = withAsync(q)
+	}
+
 	buffer := bytes.NewBuffer(requestBody)
 	resp, err := client.Call(http.MethodPatch, url+"/"+id, buffer, q)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusAccepted {
+		return client.handleAsyncResponse(resp, url, result, DefaultPollOptions())
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.ResponseError{StatusCode: resp.StatusCode}
+		return nil, errors.ResponseError{StatusCode: resp.StatusCode}
 	}
 
-	return httputil.UnmarshalResponse(resp, &result)
+	return nil, httputil.UnmarshalResponse(resp, &result)
 }
 
 func (client *serviceManagerClient) Label(url string, id string, change *types.LabelChanges, q *query.Parameters) error {
@@ -345,9 +544,16 @@ func (client *serviceManagerClient) Label(url string, id string, change *types.L
 }
 
 func (client *serviceManagerClient) Call(method string, smpath string, body io.Reader, q *query.Parameters) (*http.Response, error) {
+	return client.callWithContext(context.Background(), method, smpath, body, q)
+}
+
+// callWithContext is like Call, but binds req to ctx so that a caller which holds
+// on to its own cancellation (such as the offerings iterator) can actually abort
+// an in-flight request instead of merely stopping once it returns.
+func (client *serviceManagerClient) callWithContext(ctx context.Context, method string, smpath string, body io.Reader, q *query.Parameters) (*http.Response, error) {
 	fullURL := httputil.NormalizeURL(client.config.URL) + buildURL(smpath, q)
 
-	req, err := http.NewRequest(method, fullURL, body)
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, body)
 	if err != nil {
 		return nil, err
 	}
@@ -383,6 +589,17 @@ func (client *serviceManagerClient) Call(method string, smpath string, body io.R
 	return resp, nil
 }
 
+// withAsync returns a copy of q with an "async=true" general param added, so
+// register/update/delete called with async set request asynchronous execution
+// from Service Manager rather than relying on it to volunteer a 202 on its own.
+func withAsync(q *query.Parameters) *query.Parameters {
+	merged := &query.Parameters{GeneralParams: append(generalParams(q), "async=true")}
+	if q != nil {
+		merged.FieldQuery = q.FieldQuery
+	}
+	return merged
+}
+
 func buildURL(baseURL string, q *query.Parameters) string {
 	queryParams := q.Encode()
 	if queryParams == "" {
@@ -0,0 +1,39 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package smclient
+
+import (
+	"testing"
+
+	"github.com/Peripli/service-manager-cli/pkg/auth"
+	"github.com/Peripli/service-manager-cli/pkg/auth/basic"
+	"github.com/Peripli/service-manager-cli/pkg/auth/exec"
+	"github.com/Peripli/service-manager-cli/pkg/auth/statictoken"
+)
+
+// TestAuthStrategiesAreReachable proves that basic/static-token/exec are actually
+// registered by the time a caller goes through this package: before this package
+// blank-imported them, their init() functions never ran because nothing else in
+// the dependency graph imported them either, leaving ClientConfig.AuthStrategy
+// values for those strategies unresolvable at runtime.
+func TestAuthStrategiesAreReachable(t *testing.T) {
+	for _, name := range []string{basic.StrategyName, statictoken.StrategyName, exec.StrategyName} {
+		if _, err := auth.GetStrategy(name); err != nil {
+			t.Fatalf("expected strategy %q to be registered, got: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package smclient
+
+// ClientConfig configures how NewClientWithAuth connects to and authenticates
+// against Service Manager.
+type ClientConfig struct {
+	URL          string
+	ClientID     string
+	ClientSecret string
+	SSLDisabled  bool
+
+	// AuthStrategy selects the auth.Authenticator to use, by the name it was
+	// registered under via auth.RegisterStrategy (e.g. "oidc", "authorization_code",
+	// "password", "basic", "static-token", "exec"). Defaults to "oidc". This is the
+	// knob a "login --sso" CLI flag should set to "authorization_code" for an
+	// interactive browser login. This package does not itself expose a CLI, so the
+	// flag isn't wired up here; whatever command layer this config lives behind is
+	// expected to set AuthStrategy directly when the flag is passed.
+	AuthStrategy string
+}
@@ -0,0 +1,270 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package smclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/Peripli/service-manager/pkg/web"
+
+	"github.com/Peripli/service-manager-cli/pkg/errors"
+	"github.com/Peripli/service-manager-cli/pkg/query"
+	"github.com/Peripli/service-manager-cli/pkg/types"
+)
+
+// maxEnrichmentURLLength bounds how many ids are batched into a single "in (...)"
+// field query, so the resulting request URL stays well under common server and
+// proxy URL length limits. It intentionally leaves headroom below those limits
+// for the field name, "in (...)" syntax and URL-encoding of the query string,
+// which callers account for by chunking ids to maxEnrichmentURLLength-fieldQueryOverhead.
+const maxEnrichmentURLLength = 1500
+
+// fieldQueryOverhead is reserved out of maxEnrichmentURLLength for everything in
+// an "in (...)" field query besides the comma-joined ids themselves.
+const fieldQueryOverhead = 200
+
+// OfferingsIterator streams service offerings from Service Manager, fetching the
+// next page transparently once the local buffer is exhausted instead of
+// materializing the whole collection up front.
+type OfferingsIterator struct {
+	client *serviceManagerClient
+	q      *query.Parameters
+
+	token     string
+	started   bool
+	exhausted bool
+	buffer    []types.ServiceOffering
+}
+
+// OfferingsIterator returns an iterator over the service offerings satisfying q.
+func (client *serviceManagerClient) OfferingsIterator(q *query.Parameters) *OfferingsIterator {
+	return &OfferingsIterator{client: client, q: q}
+}
+
+// Next returns the next service offering, transparently fetching (and enriching)
+// the next page from Service Manager when the local buffer is empty. ok is false
+// once every offering has been consumed.
+func (it *OfferingsIterator) Next(ctx context.Context) (offering *types.ServiceOffering, ok bool, err error) {
+	for len(it.buffer) == 0 {
+		if it.started && it.exhausted {
+			return nil, false, nil
+		}
+		if err := it.fetchPage(ctx); err != nil {
+			return nil, false, err
+		}
+	}
+
+	next := it.buffer[0]
+	it.buffer = it.buffer[1:]
+
+	return &next, true, nil
+}
+
+func (it *OfferingsIterator) fetchPage(ctx context.Context) error {
+	it.started = true
+
+	page := &types.ServiceOfferings{}
+	token, err := it.client.listPage(ctx, page, web.ServiceOfferingsURL, withToken(it.q, it.token))
+	if err != nil {
+		return err
+	}
+
+	if err := it.client.enrichOfferings(page.ServiceOfferings, it.q); err != nil {
+		return err
+	}
+
+	it.buffer = page.ServiceOfferings
+	it.token = token
+	it.exhausted = token == ""
+
+	return nil
+}
+
+// listPage is like list, but also returns Service Manager's paging cursor
+// ("token" in the response body), so callers can decide whether to fetch
+// further pages. Unlike list, it binds the request to ctx, so a caller
+// iterating pages (like OfferingsIterator) can actually cancel a page fetch
+// that's in flight.
+func (client *serviceManagerClient) listPage(ctx context.Context, result interface{}, url string, q *query.Parameters) (string, error) {
+	response, err := client.callWithContext(ctx, http.MethodGet, url, nil, q)
+	if err != nil {
+		return "", err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", errors.ResponseError{StatusCode: response.StatusCode}
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return "", err
+	}
+
+	var page struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return "", err
+	}
+
+	return page.Token, nil
+}
+
+// withToken returns a copy of q with a "token" general param added, so the next
+// page can be requested without losing the caller's original filters.
+func withToken(q *query.Parameters, token string) *query.Parameters {
+	if token == "" {
+		return q
+	}
+
+	merged := &query.Parameters{GeneralParams: append(generalParams(q), "token="+token)}
+	if q != nil {
+		merged.FieldQuery = q.FieldQuery
+	}
+	return merged
+}
+
+func generalParams(q *query.Parameters) []string {
+	if q == nil {
+		return nil
+	}
+	return append([]string{}, q.GeneralParams...)
+}
+
+// enrichOfferings fills in Plans and BrokerName for a page of offerings using one
+// batched plans request and one batched brokers request per chunk of ids (see
+// chunkIDs) instead of two requests per offering.
+func (client *serviceManagerClient) enrichOfferings(offerings []types.ServiceOffering, q *query.Parameters) error {
+	if len(offerings) == 0 {
+		return nil
+	}
+
+	plansByOffering, err := client.plansByOfferingID(offeringIDs(offerings), q)
+	if err != nil {
+		return err
+	}
+
+	brokerNameByID, err := client.brokerNamesByID(brokerIDs(offerings), q)
+	if err != nil {
+		return err
+	}
+
+	for i := range offerings {
+		offerings[i].Plans = plansByOffering[offerings[i].ID]
+		offerings[i].BrokerName = brokerNameByID[offerings[i].BrokerID]
+	}
+
+	return nil
+}
+
+func offeringIDs(offerings []types.ServiceOffering) []string {
+	ids := make([]string, len(offerings))
+	for i, offering := range offerings {
+		ids[i] = offering.ID
+	}
+	return ids
+}
+
+func brokerIDs(offerings []types.ServiceOffering) []string {
+	seen := make(map[string]struct{}, len(offerings))
+	ids := make([]string, 0, len(offerings))
+	for _, offering := range offerings {
+		if _, ok := seen[offering.BrokerID]; ok {
+			continue
+		}
+		seen[offering.BrokerID] = struct{}{}
+		ids = append(ids, offering.BrokerID)
+	}
+	return ids
+}
+
+func (client *serviceManagerClient) plansByOfferingID(ids []string, q *query.Parameters) (map[string][]types.ServicePlan, error) {
+	plansByOffering := make(map[string][]types.ServicePlan, len(ids))
+
+	for _, chunk := range chunkIDs(ids, maxEnrichmentURLLength-fieldQueryOverhead) {
+		plans := &types.ServicePlans{}
+		err := client.list(plans, web.ServicePlansURL, &query.Parameters{
+			FieldQuery:    []string{fmt.Sprintf("service_offering_id in (%s)", strings.Join(chunk, ","))},
+			GeneralParams: generalParams(q),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, plan := range plans.ServicePlans {
+			plansByOffering[plan.ServiceOfferingID] = append(plansByOffering[plan.ServiceOfferingID], plan)
+		}
+	}
+
+	return plansByOffering, nil
+}
+
+func (client *serviceManagerClient) brokerNamesByID(ids []string, q *query.Parameters) (map[string]string, error) {
+	brokerNameByID := make(map[string]string, len(ids))
+
+	for _, chunk := range chunkIDs(ids, maxEnrichmentURLLength-fieldQueryOverhead) {
+		brokers := &types.Brokers{}
+		err := client.list(brokers, web.ServiceBrokersURL, &query.Parameters{
+			FieldQuery:    []string{fmt.Sprintf("id in (%s)", strings.Join(chunk, ","))},
+			GeneralParams: generalParams(q),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, broker := range brokers.Brokers {
+			brokerNameByID[broker.ID] = broker.Name
+		}
+	}
+
+	return brokerNameByID, nil
+}
+
+// chunkIDs groups ids, in order, into chunks whose ids plus one separating comma
+// each add up to at most maxURLLength characters. The rest of the field query is
+// not counted here. An id longer than maxURLLength forms a chunk of its own.
+func chunkIDs(ids []string, maxURLLength int) [][]string {
+	var chunks [][]string
+	var current []string
+	length := 0
+
+	for _, id := range ids {
+		addedLength := len(id) + 1 // +1 for the separating comma
+		if len(current) > 0 && length+addedLength > maxURLLength {
+			chunks = append(chunks, current)
+			current = nil
+			length = 0
+		}
+		current = append(current, id)
+		length += addedLength
+	}
+
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+
+	return chunks
+}
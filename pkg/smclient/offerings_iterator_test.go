@@ -0,0 +1,55 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package smclient
+
+import "testing"
+
+func TestChunkIDsKeepsChunksUnderLimit(t *testing.T) {
+	ids := []string{"aaaa", "bbbb", "cccc", "dddd", "eeee"}
+
+	// "aaaa,bbbb," is 10 chars; allow exactly two ids per chunk.
+	chunks := chunkIDs(ids, 10)
+
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d: %v", len(chunks), chunks)
+	}
+	for _, chunk := range chunks {
+		length := 0
+		for _, id := range chunk {
+			length += len(id) + 1
+		}
+		if length > 10 {
+			t.Fatalf("chunk %v exceeds the configured limit", chunk)
+		}
+	}
+}
+
+func TestChunkIDsSingleIDLargerThanLimitStillReturned(t *testing.T) {
+	ids := []string{"this-id-is-longer-than-the-limit"}
+
+	chunks := chunkIDs(ids, 5)
+
+	if len(chunks) != 1 || len(chunks[0]) != 1 || chunks[0][0] != ids[0] {
+		t.Fatalf("expected a single oversized id to still form its own chunk, got %v", chunks)
+	}
+}
+
+func TestChunkIDsEmpty(t *testing.T) {
+	if chunks := chunkIDs(nil, 100); chunks != nil {
+		t.Fatalf("expected no chunks for no ids, got %v", chunks)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package smclient
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/Peripli/service-manager-cli/pkg/errors"
+	"github.com/Peripli/service-manager-cli/pkg/types"
+)
+
+// operationsURL is where Service Manager exposes asynchronous operations,
+// regardless of the resource type that created them.
+const operationsURL = "/v1/operations"
+
+// PollOptions configures how Poll waits for an asynchronous operation to reach
+// a terminal state.
+type PollOptions struct {
+	// Wait, when true, makes register/update/delete poll an asynchronous operation
+	// until it is terminal or Timeout elapses. When false, they return the operation
+	// as accepted, without polling it. Poll itself does not read Wait.
+	Wait bool
+	// Initial is the delay before the first poll.
+	Initial time.Duration
+	// Max caps the delay between polls.
+	Max time.Duration
+	// Multiplier grows the delay between polls after every attempt.
+	Multiplier float64
+	// Timeout bounds the total time spent waiting. Zero means no timeout.
+	Timeout time.Duration
+}
+
+// DefaultPollOptions returns the backoff schedule used by register/update/delete
+// when they wait for an asynchronous operation to complete.
+func DefaultPollOptions() PollOptions {
+	return PollOptions{
+		Wait:       true,
+		Initial:    1 * time.Second,
+		Max:        30 * time.Second,
+		Multiplier: 2,
+		Timeout:    10 * time.Minute,
+	}
+}
+
+// GetOperation fetches the operation with id opID. resourceURL identifies the
+// resource collection (e.g. web.ServiceBrokersURL) the operation was created for
+// and is used only to enrich error messages, as Service Manager exposes all
+// operations under a single flat endpoint.
+func (client *serviceManagerClient) GetOperation(resourceURL, opID string) (*types.Operation, error) {
+	operation := &types.Operation{}
+	if err := client.list(operation, operationsURL+"/"+opID, nil); err != nil {
+		return nil, fmt.Errorf("could not get operation %s for %s: %v", opID, resourceURL, err)
+	}
+	return operation, nil
+}
+
+// Poll repeatedly fetches op until it reaches a terminal state, a timeout elapses,
+// or ctx is cancelled, backing off exponentially with jitter between attempts.
+func (client *serviceManagerClient) Poll(ctx context.Context, op *types.Operation, opts PollOptions) (*types.Operation, error) {
+	if op.IsTerminal() {
+		return op, nil
+	}
+
+	var deadline time.Time
+	if opts.Timeout > 0 {
+		deadline = time.Now().Add(opts.Timeout)
+	}
+
+	wait := opts.Initial
+	for {
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return op, fmt.Errorf("timed out waiting for operation %s to complete", op.ID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return op, ctx.Err()
+		case <-time.After(jitter(wait)):
+		}
+
+		updated := &types.Operation{}
+		if err := client.list(updated, operationsURL+"/"+op.ID, nil); err != nil {
+			return op, err
+		}
+		op = updated
+
+		if op.IsTerminal() {
+			return op, nil
+		}
+
+		wait = time.Duration(float64(wait) * opts.Multiplier)
+		if wait > opts.Max {
+			wait = opts.Max
+		}
+	}
+}
+
+// jitter returns a random duration in [d/2, d], so that concurrent pollers don't
+// all hammer the server in lockstep.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+// operationError converts a failed operation into the same error type returned
+// by synchronous SM calls, so callers only ever need to handle errors.ResponseError.
+func operationError(op *types.Operation) error {
+	return errors.ResponseError{
+		ErrorMessage: fmt.Sprintf("operation %s failed", op.ID),
+		Description:  string(op.Errors),
+	}
+}
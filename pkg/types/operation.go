@@ -0,0 +1,44 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package types
+
+import "encoding/json"
+
+// Operation states as returned by Service Manager for asynchronous resource operations.
+const (
+	OperationStateInProgress = "in progress"
+	OperationStateSucceeded  = "succeeded"
+	OperationStateFailed     = "failed"
+)
+
+// Operation represents an asynchronous broker/platform/visibility/instance/binding
+// operation tracked by Service Manager.
+type Operation struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	State      string          `json:"state"`
+	ResourceID string          `json:"resource_id"`
+	Errors     json.RawMessage `json:"errors,omitempty"`
+	CreatedAt  string          `json:"created_at,omitempty"`
+	UpdatedAt  string          `json:"updated_at,omitempty"`
+}
+
+// IsTerminal returns true when the operation has reached a state from which it
+// will not transition further.
+func (o *Operation) IsTerminal() bool {
+	return o.State == OperationStateSucceeded || o.State == OperationStateFailed
+}
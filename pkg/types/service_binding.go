@@ -0,0 +1,35 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package types
+
+// ServiceBinding represents an Open Service Broker service binding managed by
+// Service Manager.
+type ServiceBinding struct {
+	ID                string                 `json:"id,omitempty"`
+	Name              string                 `json:"name"`
+	ServiceInstanceID string                 `json:"service_instance_id"`
+	Parameters        map[string]interface{} `json:"parameters,omitempty"`
+	Credentials       map[string]interface{} `json:"credentials,omitempty"`
+	Ready             bool                   `json:"ready,omitempty"`
+	CreatedAt         string                 `json:"created_at,omitempty"`
+	UpdatedAt         string                 `json:"updated_at,omitempty"`
+}
+
+// ServiceBindings wraps an array of service bindings, as returned by the service manager
+type ServiceBindings struct {
+	ServiceBindings []ServiceBinding `json:"service_bindings"`
+}
@@ -0,0 +1,35 @@
+/*
+ * Copyright 2018 The Service Manager Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package types
+
+// ServiceInstance represents an Open Service Broker service instance managed by
+// Service Manager.
+type ServiceInstance struct {
+	ID            string                 `json:"id,omitempty"`
+	Name          string                 `json:"name"`
+	ServicePlanID string                 `json:"service_plan_id"`
+	PlatformID    string                 `json:"platform_id,omitempty"`
+	Parameters    map[string]interface{} `json:"parameters,omitempty"`
+	Ready         bool                   `json:"ready,omitempty"`
+	CreatedAt     string                 `json:"created_at,omitempty"`
+	UpdatedAt     string                 `json:"updated_at,omitempty"`
+}
+
+// ServiceInstances wraps an array of service instances, as returned by the service manager
+type ServiceInstances struct {
+	ServiceInstances []ServiceInstance `json:"service_instances"`
+}